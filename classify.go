@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind represents the broad category a file appears to belong to.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindMusic
+	KindAudiobook
+	KindFilm
+	KindSeries
+	KindProgram
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindMusic:
+		return "Music"
+	case KindAudiobook:
+		return "Audiobook"
+	case KindFilm:
+		return "Film"
+	case KindSeries:
+		return "Series"
+	case KindProgram:
+		return "Program"
+	default:
+		return "Unknown"
+	}
+}
+
+// kindPrecedence ranks kinds from most to least specific. When two rules
+// match the same file, the lower-ranked (more specific) kind wins; this is
+// what lets a Program rule (e.g. "*.S01E*.exe") beat a looser Film rule.
+var kindPrecedence = map[Kind]int{
+	KindProgram:   0,
+	KindSeries:    1,
+	KindFilm:      2,
+	KindAudiobook: 3,
+	KindMusic:     4,
+	KindUnknown:   5,
+}
+
+// replace reports whether k should replace other as the classification for
+// a path, i.e. whether k is strictly more specific than other.
+func (k Kind) replace(other Kind) bool {
+	return kindPrecedence[k] < kindPrecedence[other]
+}
+
+// Classification records the resolved Kind for each path seen so far,
+// applying Kind precedence when the same path is classified more than once.
+type Classification map[string]Kind
+
+// Add records k as the classification for path. If path was already
+// classified, the higher-precedence Kind wins and the decision is logged.
+func (c Classification) Add(path string, k Kind) {
+	existing, ok := c[path]
+	if !ok {
+		c[path] = k
+		return
+	}
+	if existing == k {
+		return
+	}
+	if k.replace(existing) {
+		fmt.Printf("classify: %s reclassified %s -> %s\n", path, existing, k)
+		c[path] = k
+	} else {
+		fmt.Printf("classify: %s kept as %s (ignoring lower-precedence match %s)\n", path, existing, k)
+	}
+}
+
+// classifierRule pairs a compiled regexp with the Kind it identifies.
+type classifierRule struct {
+	kind Kind
+	re   *regexp.Regexp
+}
+
+// defaultExtKinds is consulted when no regexp rule matches a file, as a
+// last-resort fallback based on extension alone.
+var defaultExtKinds = map[string]Kind{
+	".mp3":  KindMusic,
+	".flac": KindMusic,
+	".m4a":  KindMusic,
+	".wav":  KindMusic,
+	".mkv":  KindFilm,
+	".mp4":  KindFilm,
+	".avi":  KindFilm,
+	".exe":  KindProgram,
+	".msi":  KindProgram,
+}
+
+// Classifier decides the Kind of a file from user-configurable regexps,
+// falling back to extension and directory-name hints when nothing matches.
+type Classifier struct {
+	rules []classifierRule
+}
+
+var kindNames = map[string]Kind{
+	"music":     KindMusic,
+	"audiobook": KindAudiobook,
+	"podcast":   KindAudiobook,
+	"film":      KindFilm,
+	"movie":     KindFilm,
+	"series":    KindSeries,
+	"program":   KindProgram,
+}
+
+// rulesFile is the on-disk shape of a classify rules file: a kind name
+// mapped to a list of regexp patterns matched against the file's base name.
+type rulesFile struct {
+	Rules map[string][]string `json:"rules"`
+}
+
+// loadClassifierRules reads a JSON rules file and compiles it into a
+// Classifier. Every key in the file is looked up through kindNames, so the
+// "movie"/"podcast" aliases accepted by -only work here too. Rules are tried
+// in kindPrecedence order (most to least specific, e.g. Program before
+// Music) regardless of the order keys appear in the file, so that absent an
+// explicit ordering the more specific kinds are still tried first; within
+// the same kind, aliases are tried in a fixed (alphabetical) order so
+// results are reproducible.
+func loadClassifierRules(path string) (*Classifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules file: %w", err)
+	}
+
+	var parsed rulesFile
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("parse rules file: %w", err)
+	}
+
+	var names []string
+	for name := range parsed.Rules {
+		if _, ok := kindNames[strings.ToLower(name)]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ki, kj := kindNames[strings.ToLower(names[i])], kindNames[strings.ToLower(names[j])]
+		if ki != kj {
+			return kindPrecedence[ki] < kindPrecedence[kj]
+		}
+		return names[i] < names[j]
+	})
+
+	var rules []classifierRule
+	for _, name := range names {
+		kind := kindNames[strings.ToLower(name)]
+		for _, pattern := range parsed.Rules[name] {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q for kind %s: %w", pattern, name, err)
+			}
+			rules = append(rules, classifierRule{kind: kind, re: re})
+		}
+	}
+
+	return &Classifier{rules: rules}, nil
+}
+
+// Classify decides the Kind of path based on its configured rules, falling
+// back to the file extension and then the parent directory name.
+func (c *Classifier) Classify(path string) Kind {
+	name := filepath.Base(path)
+
+	for _, rule := range c.rules {
+		if rule.re.MatchString(name) {
+			return rule.kind
+		}
+	}
+
+	if kind, ok := defaultExtKinds[strings.ToLower(filepath.Ext(path))]; ok {
+		return kind
+	}
+
+	dir := strings.ToLower(filepath.Base(filepath.Dir(path)))
+	for name, kind := range kindNames {
+		if strings.Contains(dir, name) {
+			return kind
+		}
+	}
+
+	return KindUnknown
+}
+
+// classifyCommand is the subcommand that classifies files by Kind and
+// optionally routes each Kind to a different destination directory.
+func classifyCommand(args []string) {
+	fs := flag.NewFlagSet("classify", flag.ExitOnError)
+	dir := fs.String("dir", ".", "Directory path to scan")
+	rulesPath := fs.String("rules", "", "Path to a JSON rules file (see docs for format); required")
+	only := fs.String("only", "", "Comma-separated list of kinds to report/route (default: all)")
+	route := fs.String("route", "", "If set, move each classified file into <route>/<Kind>/<basename> instead of just reporting")
+	dryRun := fs.Bool("dry-run", false, "With -route, preview moves without touching any files")
+	autoYes := fs.Bool("y", false, "With -route, auto-confirm moves without asking")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: audiotool classify [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Classify files as Music, Audiobook, Film, Series, or Program\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  audiotool classify -dir /path/to/media -rules rules.json\n")
+		fmt.Fprintf(os.Stderr, "  audiotool classify -dir /path/to/media -rules rules.json -only film,series\n")
+		fmt.Fprintf(os.Stderr, "  audiotool classify -dir /path/to/media -rules rules.json -route /path/to/sorted -y\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *rulesPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -rules is required\n")
+		os.Exit(1)
+	}
+
+	classifier, err := loadClassifierRules(*rulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load rules: %v\n", err)
+		os.Exit(1)
+	}
+
+	absDir, err := filepath.Abs(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: unable to get absolute path: %v\n", err)
+		os.Exit(1)
+	}
+
+	files, err := collectFiles(absDir, nil, nil, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to collect files: %v\n", err)
+		os.Exit(1)
+	}
+
+	var onlyKinds map[Kind]bool
+	if *only != "" {
+		onlyKinds = make(map[Kind]bool)
+		for _, name := range strings.Split(*only, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if kind, ok := kindNames[name]; ok {
+				onlyKinds[kind] = true
+			}
+		}
+	}
+
+	classification := make(Classification)
+	for _, file := range files {
+		classification.Add(file, classifier.Classify(file))
+	}
+
+	counts := make(map[Kind]int)
+	for file, kind := range classification {
+		counts[kind]++
+		if onlyKinds != nil && !onlyKinds[kind] {
+			continue
+		}
+		fmt.Printf("%-10s %s\n", kind, file)
+	}
+
+	fmt.Println("\nSummary:")
+	for kind := KindMusic; kind <= KindProgram; kind++ {
+		fmt.Printf("  %-10s %d\n", kind, counts[kind])
+	}
+	if n := counts[KindUnknown]; n > 0 {
+		fmt.Printf("  %-10s %d\n", KindUnknown, n)
+	}
+
+	if *route != "" {
+		routeFiles(classification, onlyKinds, *route, *dryRun, *autoYes)
+	}
+}
+
+// routeFiles moves each classified file into routeRoot/<Kind>/<basename>,
+// skipping kinds excluded by onlyKinds (nil means route everything).
+func routeFiles(classification Classification, onlyKinds map[Kind]bool, routeRoot string, dryRun bool, autoYes bool) {
+	absRoute, err := filepath.Abs(routeRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: unable to get absolute path: %v\n", err)
+		os.Exit(1)
+	}
+
+	type move struct {
+		oldPath string
+		newPath string
+	}
+	var moves []move
+
+	for file, kind := range classification {
+		if onlyKinds != nil && !onlyKinds[kind] {
+			continue
+		}
+		newPath := filepath.Join(absRoute, kind.String(), filepath.Base(file))
+		if newPath == file {
+			continue
+		}
+		moves = append(moves, move{oldPath: file, newPath: newPath})
+	}
+
+	if len(moves) == 0 {
+		fmt.Println("\nNothing to route")
+		return
+	}
+
+	fmt.Printf("\nRoute preview (showing first 5):\n")
+	displayCount := 5
+	if len(moves) < displayCount {
+		displayCount = len(moves)
+	}
+	for i := 0; i < displayCount; i++ {
+		fmt.Printf("  %s\n  -> %s\n\n", moves[i].oldPath, moves[i].newPath)
+	}
+	if len(moves) > displayCount {
+		fmt.Printf("  ... and %d more files\n\n", len(moves)-displayCount)
+	}
+
+	if dryRun {
+		fmt.Println("[Preview Mode] No actual moving performed")
+		return
+	}
+
+	proceed := autoYes
+	if !autoYes {
+		fmt.Printf("Proceed to route these %d files? (y/n): ", len(moves))
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		response = strings.ToLower(strings.TrimSpace(response))
+		proceed = response == "y" || response == "yes"
+	}
+	if !proceed {
+		fmt.Println("Cancelled")
+		return
+	}
+
+	journalPath := defaultJournalPath(absRoute)
+	batchID := newBatchID()
+
+	successCount := 0
+	for _, m := range moves {
+		if err := os.MkdirAll(filepath.Dir(m.newPath), 0755); err != nil {
+			fmt.Printf("Error: failed to create directory for %s: %v\n", m.newPath, err)
+			continue
+		}
+		if _, err := os.Stat(m.newPath); err == nil {
+			fmt.Printf("Skipping %s: a file already exists at %s\n", m.oldPath, m.newPath)
+			continue
+		}
+		if err := os.Rename(m.oldPath, m.newPath); err != nil {
+			fmt.Printf("Error: failed to move %s: %v\n", m.oldPath, err)
+			continue
+		}
+		if err := recordRename(journalPath, m.oldPath, m.newPath, batchID); err != nil {
+			fmt.Printf("Warning: move succeeded but failed to journal it: %v\n", err)
+		}
+		successCount++
+	}
+
+	fmt.Printf("Successfully routed %d/%d files\n", successCount, len(moves))
+}
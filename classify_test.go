@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKindReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        Kind
+		other    Kind
+		expected bool
+	}{
+		{"program beats film", KindProgram, KindFilm, true},
+		{"film does not beat program", KindFilm, KindProgram, false},
+		{"series beats music", KindSeries, KindMusic, true},
+		{"same kind does not replace", KindMusic, KindMusic, false},
+		{"anything beats unknown", KindMusic, KindUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.k.replace(tt.other); result != tt.expected {
+				t.Errorf("Kind(%s).replace(%s) = %v, want %v", tt.k, tt.other, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClassificationAddPrecedence(t *testing.T) {
+	c := make(Classification)
+
+	c.Add("show.mkv", KindFilm)
+	if c["show.mkv"] != KindFilm {
+		t.Fatalf("expected initial classification Film, got %s", c["show.mkv"])
+	}
+
+	// A lower-precedence match should not override an existing classification.
+	c.Add("show.mkv", KindMusic)
+	if c["show.mkv"] != KindFilm {
+		t.Errorf("expected Film to be kept over lower-precedence Music, got %s", c["show.mkv"])
+	}
+
+	// A higher-precedence match should override.
+	c.Add("show.mkv", KindSeries)
+	if c["show.mkv"] != KindSeries {
+		t.Errorf("expected Series to replace Film, got %s", c["show.mkv"])
+	}
+}
+
+func TestLoadClassifierRulesAcceptsAliasKeys(t *testing.T) {
+	rulesJSON := `{"rules": {"movie": ["\\.movie\\."], "podcast": ["\\.podcast\\."]}}`
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(rulesJSON), 0644); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+
+	classifier, err := loadClassifierRules(path)
+	if err != nil {
+		t.Fatalf("loadClassifierRules() returned error: %v", err)
+	}
+	if len(classifier.rules) != 2 {
+		t.Fatalf("expected 2 rules loaded from alias keys, got %d", len(classifier.rules))
+	}
+
+	if result := classifier.Classify("thing.movie.mkv"); result != KindFilm {
+		t.Errorf("Classify() via \"movie\" alias = %s, want %s", result, KindFilm)
+	}
+	if result := classifier.Classify("thing.podcast.mp3"); result != KindAudiobook {
+		t.Errorf("Classify() via \"podcast\" alias = %s, want %s", result, KindAudiobook)
+	}
+}
+
+func TestLoadClassifierRulesOrdersByPrecedence(t *testing.T) {
+	// "music" is listed before "program" in the file; the loaded rules must
+	// still be tried Program-first so a file matching both rules classifies
+	// as the more specific Program kind.
+	rulesJSON := `{"rules": {"music": [".*"], "program": [".*\\.exe$"]}}`
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(rulesJSON), 0644); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+
+	classifier, err := loadClassifierRules(path)
+	if err != nil {
+		t.Fatalf("loadClassifierRules() returned error: %v", err)
+	}
+
+	if result := classifier.Classify("installer.exe"); result != KindProgram {
+		t.Errorf("Classify() = %s, want %s", result, KindProgram)
+	}
+}
+
+func TestClassifierExtensionFallback(t *testing.T) {
+	c := &Classifier{}
+
+	tests := []struct {
+		path     string
+		expected Kind
+	}{
+		{"song.mp3", KindMusic},
+		{"movie.mkv", KindFilm},
+		{"setup.exe", KindProgram},
+		{"notes.txt", KindUnknown},
+	}
+
+	for _, tt := range tests {
+		if result := c.Classify(tt.path); result != tt.expected {
+			t.Errorf("Classify(%q) = %s, want %s", tt.path, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// Config gathers the options that drive a removeprefix run. It exists so
+// that -collection mode (and processDirectory's other callers) can pass a
+// single value around instead of the flag list growing on every call site.
+type Config struct {
+	Collection bool // Treat the scanned directory as an Artist/Album tree and process one album per unit
+	Force      bool // Auto-confirm all operations without asking
+	DryRun     bool // Preview mode, don't actually rename files
+	Workers    int  // Number of album directories to process concurrently in collection mode
+
+	JournalPath string // Append-only rename journal, consumed by `audiotool undo`
+	BatchID     string // Groups every rename from this invocation for `undo --batch`
+	Separators  SeparatorSet
+}
+
+// BundleFiles groups file indices by parent directory, preserving the order
+// in which each directory was first seen. It is the collection-mode
+// counterpart to groupFilesByDirectory: callers that need to track files by
+// position (e.g. to report progress per bundle) can work with indices
+// instead of re-deriving directories from paths.
+func BundleFiles(files []string) [][]int {
+	order := make([]string, 0)
+	groups := make(map[string][]int)
+
+	for i, file := range files {
+		dir := filepath.Dir(file)
+		if _, seen := groups[dir]; !seen {
+			order = append(order, dir)
+		}
+		groups[dir] = append(groups[dir], i)
+	}
+
+	bundles := make([][]int, 0, len(order))
+	for _, dir := range order {
+		bundles = append(bundles, groups[dir])
+	}
+	return bundles
+}
+
+// progressReporter prints a running "N/total bundles processed" line as
+// workers complete album directories. It is safe for concurrent use.
+type progressReporter struct {
+	mu        sync.Mutex
+	total     int
+	completed int
+}
+
+func newProgressReporter(total int) *progressReporter {
+	return &progressReporter{total: total}
+}
+
+func (p *progressReporter) reportDone(dir string) {
+	p.mu.Lock()
+	p.completed++
+	fmt.Printf("[%d/%d] Finished %s\n", p.completed, p.total, dir)
+	p.mu.Unlock()
+}
+
+// processCollection processes each album directory (bundle) as one unit
+// using a worker pool sized to cfg.Workers, reporting progress as bundles
+// complete. Workers default to runtime.NumCPU() when cfg.Workers <= 0.
+func processCollection(cfg Config, files []string) {
+	bundles := BundleFiles(files)
+	if len(bundles) == 0 {
+		return
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(bundles) {
+		workers = len(bundles)
+	}
+
+	reporter := newProgressReporter(len(bundles))
+	jobs := make(chan []int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for bundle := range jobs {
+				bundleFiles := make([]string, len(bundle))
+				for i, idx := range bundle {
+					bundleFiles[i] = files[idx]
+				}
+				dir := filepath.Dir(bundleFiles[0])
+				if err := processDirectory(dir, bundleFiles, cfg); err != nil {
+					fmt.Printf("Error: failed to process directory %s: %v\n", dir, err)
+				}
+				reporter.reportDone(dir)
+			}
+		}()
+	}
+
+	for _, bundle := range bundles {
+		jobs <- bundle
+	}
+	close(jobs)
+	wg.Wait()
+}
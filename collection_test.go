@@ -0,0 +1,30 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBundleFiles(t *testing.T) {
+	files := []string{
+		"ArtistA/AlbumA/01.mp3",
+		"ArtistA/AlbumA/02.mp3",
+		"ArtistB/AlbumB/01.mp3",
+		"ArtistA/AlbumA/03.mp3",
+	}
+
+	expected := [][]int{
+		{0, 1, 3},
+		{2},
+	}
+
+	if result := BundleFiles(files); !reflect.DeepEqual(result, expected) {
+		t.Errorf("BundleFiles() = %v, want %v", result, expected)
+	}
+}
+
+func TestBundleFilesEmpty(t *testing.T) {
+	if result := BundleFiles(nil); len(result) != 0 {
+		t.Errorf("BundleFiles(nil) = %v, want empty", result)
+	}
+}
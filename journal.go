@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultJournalName = ".audiotool-journal.jsonl"
+
+// journalRecord is one append-only entry describing a single executed
+// rename, written so that `audiotool undo` can reverse it later.
+type journalRecord struct {
+	Timestamp string `json:"timestamp"`
+	OldPath   string `json:"old_path"`
+	NewPath   string `json:"new_path"`
+	Operation string `json:"operation"`
+	BatchID   string `json:"batch_id"`
+	Size      int64  `json:"size"`     // size of new_path right after the rename, for undo collision detection
+	ModTime   string `json:"mod_time"` // mtime of new_path right after the rename, for undo collision detection
+}
+
+// defaultJournalPath returns the default journal location for a directory
+// that processDirectory/organizeCommand operated on.
+func defaultJournalPath(dir string) string {
+	return filepath.Join(dir, defaultJournalName)
+}
+
+// newBatchID returns an identifier shared by every rename performed in a
+// single command invocation, so that `undo --batch <id>` can target it.
+func newBatchID() string {
+	return time.Now().UTC().Format("20060102T150405.000000000Z")
+}
+
+// appendJournal appends a single record to the journal file at path,
+// creating it if necessary.
+func appendJournal(path string, rec journalRecord) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open journal %s: %w", path, err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal journal record: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write journal %s: %w", path, err)
+	}
+	return nil
+}
+
+// recordRename appends a "rename" record for a completed os.Rename, capturing
+// the post-rename size/mtime of newPath so undo can later detect whether the
+// file was touched again before being reverted.
+func recordRename(journalPath, oldPath, newPath, batchID string) error {
+	info, err := os.Stat(newPath)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", newPath, err)
+	}
+	return appendJournal(journalPath, journalRecord{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		OldPath:   oldPath,
+		NewPath:   newPath,
+		Operation: "rename",
+		BatchID:   batchID,
+		Size:      info.Size(),
+		ModTime:   info.ModTime().UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// readJournal parses every record in an append-only journal file, in the
+// order they were written.
+func readJournal(path string) ([]journalRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open journal %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var records []journalRecord
+	scanner := bufio.NewScanner(f)
+	// Journal lines are small JSON objects, but raise the buffer limit well
+	// past bufio's 64KiB default in case of unusually long paths.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec journalRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("parse journal line: %w", err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal %s: %w", path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendAndReadJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	journalPath := filepath.Join(tmpDir, ".audiotool-journal.jsonl")
+
+	records := []journalRecord{
+		{Timestamp: "2026-01-01T00:00:00Z", OldPath: "a.mp3", NewPath: "b.mp3", Operation: "rename", BatchID: "batch-1"},
+		{Timestamp: "2026-01-01T00:00:01Z", OldPath: "c.mp3", NewPath: "d.mp3", Operation: "rename", BatchID: "batch-1"},
+	}
+	for _, rec := range records {
+		if err := appendJournal(journalPath, rec); err != nil {
+			t.Fatalf("appendJournal() error = %v", err)
+		}
+	}
+
+	got, err := readJournal(journalPath)
+	if err != nil {
+		t.Fatalf("readJournal() error = %v", err)
+	}
+	if len(got) != len(records) {
+		t.Fatalf("readJournal() got %d records, want %d", len(got), len(records))
+	}
+	for i, rec := range records {
+		if got[i] != rec {
+			t.Errorf("record %d = %+v, want %+v", i, got[i], rec)
+		}
+	}
+}
+
+func TestRecordRename(t *testing.T) {
+	tmpDir := t.TempDir()
+	newPath := filepath.Join(tmpDir, "renamed.mp3")
+	if err := os.WriteFile(newPath, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	journalPath := filepath.Join(tmpDir, ".audiotool-journal.jsonl")
+	if err := recordRename(journalPath, filepath.Join(tmpDir, "original.mp3"), newPath, "batch-1"); err != nil {
+		t.Fatalf("recordRename() error = %v", err)
+	}
+
+	records, err := readJournal(journalPath)
+	if err != nil {
+		t.Fatalf("readJournal() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if records[0].Size != int64(len("data")) {
+		t.Errorf("Size = %d, want %d", records[0].Size, len("data"))
+	}
+	if records[0].Operation != "rename" || records[0].BatchID != "batch-1" {
+		t.Errorf("unexpected record: %+v", records[0])
+	}
+}
@@ -15,134 +15,13 @@ import (
 
 const version = "1.0.0"
 
-// findCommonPrefix finds the longest common prefix among all strings
-func findCommonPrefix(strs []string) string {
-	if len(strs) == 0 {
-		return ""
-	}
-	if len(strs) == 1 {
-		return ""
-	}
-
-	// Find the shortest string length
-	minLen := len(strs[0])
-	for _, s := range strs {
-		if len(s) < minLen {
-			minLen = len(s)
-		}
-	}
-
-	// Byte-by-byte comparison to find common prefix
-	prefixLen := 0
-	for i := 0; i < minLen; i++ {
-		char := strs[0][i]
-		allMatch := true
-		for _, s := range strs {
-			if s[i] != char {
-				allMatch = false
-				break
-			}
-		}
-		if allMatch {
-			prefixLen = i + 1
-		} else {
-			break
-		}
-	}
-
-	if prefixLen == 0 {
-		return ""
-	}
-
-	// Extract raw byte prefix
-	prefix := strs[0][:prefixLen]
-
-	// Smart trimming: ensure cutting at separator positions
-	// Search backwards for the last separator
-	lastSep := -1
-	for i := len(prefix) - 1; i >= 0; i-- {
-		ch := prefix[i]
-		if ch == '-' || ch == '_' || ch == ' ' || ch == ')' || ch == ']' {
-			lastSep = i + 1
-			break
-		}
-		// Check for Chinese 】 symbol (UTF-8: E3 80 91)
-		if i >= 2 && prefix[i-2] == 0xE3 && prefix[i-1] == 0x80 && prefix[i] == 0x91 {
-			lastSep = i + 1
-			break
-		}
-	}
-
-	if lastSep > 0 && lastSep < len(prefix) {
-		return prefix[:lastSep]
-	}
-
-	return prefix
-}
-
-// findMajorityPrefix finds common prefix for the majority of files (at least 70%)
-// This helps handle cases where a few outlier files don't share the common prefix
-func findMajorityPrefix(strs []string) string {
-	if len(strs) < 2 {
-		return ""
-	}
-
-	// Try to find a prefix that works for at least 70% of files
-	threshold := int(float64(len(strs)) * 0.7)
-	if threshold < 2 {
-		threshold = 2
-	}
-
-	// Try each file as a potential prefix source
-	bestPrefix := ""
-	bestMatchCount := 0
-
-	for _, sourceFile := range strs {
-		// Try different prefix lengths from this file
-		for length := len(sourceFile); length >= 3; length-- {
-			potentialPrefix := sourceFile[:length]
-
-			// Count how many files have this prefix
-			matchCount := 0
-			for _, s := range strs {
-				if strings.HasPrefix(s, potentialPrefix) {
-					matchCount++
-				}
-			}
-
-			// If this prefix matches more files than our current best
-			if matchCount >= threshold && matchCount > bestMatchCount {
-				// Find the last separator position
-				lastSep := -1
-				for i := len(potentialPrefix) - 1; i >= 0; i-- {
-					ch := potentialPrefix[i]
-					if ch == '-' || ch == '_' || ch == ' ' || ch == ')' || ch == ']' {
-						lastSep = i + 1
-						break
-					}
-					// Check for Chinese 】 symbol (UTF-8: E3 80 91)
-					if i >= 2 && potentialPrefix[i-2] == 0xE3 && potentialPrefix[i-1] == 0x80 && potentialPrefix[i] == 0x91 {
-						lastSep = i + 1
-						break
-					}
-				}
-
-				if lastSep > 0 && lastSep < len(potentialPrefix) {
-					finalPrefix := potentialPrefix[:lastSep]
-					if len(strings.TrimSpace(finalPrefix)) >= 3 {
-						bestPrefix = finalPrefix
-						bestMatchCount = matchCount
-					}
-				}
-			}
-		}
-	}
-
-	return bestPrefix
-} // collectFiles recursively collects all files in a directory
-// excludeDirs: directories to skip (e.g., @eaDir, .git)
+// collectFiles recursively collects all files in a directory.
+// includePatterns/excludePatterns are shell globs (see Match) matched
+// against each entry's path relative to root, with "/" as the separator
+// regardless of OS (e.g. "**/@eaDir/**", "covers/*.jpg"). A nil or empty
+// includePatterns means "include everything" unless excluded.
 // extensions: allowed file extensions (e.g., .mp3, .m4a); empty means all files
-func collectFiles(root string, excludeDirs []string, extensions []string) ([]string, error) {
+func collectFiles(root string, includePatterns []string, excludePatterns []string, extensions []string) ([]string, error) {
 	var files []string
 
 	// Convert extensions to lowercase for case-insensitive matching
@@ -154,31 +33,58 @@ func collectFiles(root string, excludeDirs []string, extensions []string) ([]str
 		extMap[strings.ToLower(ext)] = true
 	}
 
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(walkPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip excluded directories
+		rel, relErr := filepath.Rel(root, walkPath)
+		if relErr != nil {
+			return relErr
+		}
+		if rel == "." {
+			return nil
+		}
+		relSlash := filepath.ToSlash(rel)
+
 		if info.IsDir() {
-			dirName := filepath.Base(path)
-			for _, excludeDir := range excludeDirs {
-				if dirName == excludeDir {
+			for _, pattern := range excludePatterns {
+				matched, partial := Match(pattern, relSlash, true)
+				if matched && !partial {
 					return filepath.SkipDir
 				}
 			}
 			return nil
 		}
 
+		for _, pattern := range excludePatterns {
+			if matched, _ := Match(pattern, relSlash, true); matched {
+				return nil
+			}
+		}
+
+		if len(includePatterns) > 0 {
+			included := false
+			for _, pattern := range includePatterns {
+				if matched, _ := Match(pattern, relSlash, true); matched {
+					included = true
+					break
+				}
+			}
+			if !included {
+				return nil
+			}
+		}
+
 		// Filter by extension if specified
 		if len(extMap) > 0 {
-			ext := strings.ToLower(filepath.Ext(path))
+			ext := strings.ToLower(filepath.Ext(walkPath))
 			if !extMap[ext] {
 				return nil
 			}
 		}
 
-		files = append(files, path)
+		files = append(files, walkPath)
 		return nil
 	})
 	return files, err
@@ -194,8 +100,12 @@ func groupFilesByDirectory(files []string) map[string][]string {
 	return groups
 }
 
-// processDirectory processes files in a single directory
-func processDirectory(dir string, files []string, dryRun bool, autoYes bool) error {
+// processDirectory processes files in a single directory. Every executed
+// rename is appended to cfg.JournalPath (see journal.go) tagged with
+// cfg.BatchID so that `audiotool undo` can reverse it later. cfg.Separators
+// controls which runes are treated as prefix-trimming boundaries (see
+// SeparatorSet).
+func processDirectory(dir string, files []string, cfg Config) error {
 	if len(files) < 2 {
 		return nil // Less than 2 files, no processing needed
 	}
@@ -207,11 +117,11 @@ func processDirectory(dir string, files []string, dryRun bool, autoYes bool) err
 	}
 
 	// Find common prefix for all files
-	prefix := findCommonPrefix(filenames)
+	prefix := findCommonPrefixWithSeparators(filenames, cfg.Separators)
 
 	// If prefix is too short, try smart filtering: find prefix for majority of files
 	if prefix == "" || len(strings.TrimSpace(prefix)) < 3 {
-		prefix = findMajorityPrefix(filenames)
+		prefix = findMajorityPrefixWithSeparators(filenames, cfg.Separators)
 		if prefix == "" || len(strings.TrimSpace(prefix)) < 3 {
 			return nil // Still no good prefix, skip processing
 		}
@@ -283,14 +193,14 @@ func processDirectory(dir string, files []string, dryRun bool, autoYes bool) err
 		fmt.Printf("  ... and %d more files\n\n", len(plans)-displayCount)
 	}
 
-	if dryRun {
+	if cfg.DryRun {
 		fmt.Println("[Preview Mode] No actual renaming performed")
 		return nil
 	}
 
 	// Ask for confirmation
-	proceed := autoYes
-	if !autoYes {
+	proceed := cfg.Force
+	if !cfg.Force {
 		fmt.Printf("Proceed to rename these %d files? (y/n): ", len(plans))
 		reader := bufio.NewReader(os.Stdin)
 		response, err := reader.ReadString('\n')
@@ -312,9 +222,12 @@ func processDirectory(dir string, files []string, dryRun bool, autoYes bool) err
 		err := os.Rename(plan.OldPath, plan.NewPath)
 		if err != nil {
 			fmt.Printf("Error: failed to rename %s: %v\n", plan.OldName, err)
-		} else {
-			successCount++
+			continue
+		}
+		if err := recordRename(cfg.JournalPath, plan.OldPath, plan.NewPath, cfg.BatchID); err != nil {
+			fmt.Printf("Warning: rename succeeded but failed to journal it: %v\n", err)
 		}
+		successCount++
 	}
 
 	fmt.Printf("Successfully renamed %d/%d files\n", successCount, len(plans))
@@ -327,8 +240,13 @@ func removePrefixCommand(args []string) {
 	dir := fs.String("dir", ".", "Directory path to process")
 	dryRun := fs.Bool("dry-run", false, "Preview mode, don't actually rename files")
 	autoYes := fs.Bool("y", false, "Auto-confirm all operations without asking")
-	excludeDirs := fs.String("exclude-dirs", "@eaDir", "Comma-separated list of directory names to exclude")
+	exclude := fs.String("exclude", "**/@eaDir/**,**/@eaDir", "Comma-separated list of glob patterns to exclude, matched against each path relative to -dir (e.g. '**/@eaDir/**','**/.DS_Store')")
+	include := fs.String("include", "", "Comma-separated list of glob patterns to include; empty means include everything not excluded")
 	exts := fs.String("exts", "", "Comma-separated list of file extensions to process (e.g., mp3,m4a,flac,wav,mp4,mkv)")
+	collection := fs.Bool("collection", false, "Treat -dir as an Artist/Album tree and process each album directory concurrently (requires -y or -dry-run: concurrent workers can't share one interactive prompt)")
+	workers := fs.Int("workers", 0, "Number of album directories to process concurrently in -collection mode (default: number of CPUs)")
+	journal := fs.String("journal", "", "Path to the rename journal, consumed by 'audiotool undo' (default: .audiotool-journal.jsonl in -dir)")
+	separators := fs.String("separators", "", "Comma-separated list of extra single-rune prefix separators, added to the built-in set (e.g. '】,],),-,_, ')")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: audiotool removeprefix [options]\n\n")
@@ -339,7 +257,10 @@ func removePrefixCommand(args []string) {
 		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/music -dry-run\n")
 		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/music -y\n")
 		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/music -exts mp3,m4a,flac\n")
-		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/music -exclude-dirs @eaDir,.git\n")
+		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/music -exclude '**/@eaDir/**','**/.DS_Store'\n")
+		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/library -collection -workers 4 -y\n")
+		fmt.Fprintf(os.Stderr, "  audiotool undo -dir /path/to/music --last\n")
+		fmt.Fprintf(os.Stderr, "  audiotool removeprefix -dir /path/to/music -separators '～,·'\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
@@ -371,14 +292,23 @@ func removePrefixCommand(args []string) {
 	}
 	fmt.Println()
 
-	// Parse exclude directories
-	var excludeDirList []string
-	if *excludeDirs != "" {
-		excludeDirList = strings.Split(*excludeDirs, ",")
-		for i, dir := range excludeDirList {
-			excludeDirList[i] = strings.TrimSpace(dir)
+	// Parse exclude/include patterns
+	var excludeList []string
+	if *exclude != "" {
+		excludeList = strings.Split(*exclude, ",")
+		for i, pattern := range excludeList {
+			excludeList[i] = strings.TrimSpace(pattern)
+		}
+		fmt.Printf("Excluding patterns: %v\n", excludeList)
+	}
+
+	var includeList []string
+	if *include != "" {
+		includeList = strings.Split(*include, ",")
+		for i, pattern := range includeList {
+			includeList[i] = strings.TrimSpace(pattern)
 		}
-		fmt.Printf("Excluding directories: %v\n", excludeDirList)
+		fmt.Printf("Including only patterns: %v\n", includeList)
 	}
 
 	// Parse file extensions
@@ -392,7 +322,7 @@ func removePrefixCommand(args []string) {
 	}
 
 	// Collect all files
-	files, err := collectFiles(absDir, excludeDirList, extList)
+	files, err := collectFiles(absDir, includeList, excludeList, extList)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to collect files: %v\n", err)
 		os.Exit(1)
@@ -405,15 +335,40 @@ func removePrefixCommand(args []string) {
 
 	fmt.Printf("Found %d files in total\n", len(files))
 
-	// Group by directory
-	groups := groupFilesByDirectory(files)
-	fmt.Printf("Involving %d directories\n", len(groups))
+	journalPath := *journal
+	if journalPath == "" {
+		journalPath = defaultJournalPath(absDir)
+	}
 
-	// Process each directory
-	for dir, dirFiles := range groups {
-		err := processDirectory(dir, dirFiles, *dryRun, *autoYes)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to process directory %s: %v\n", dir, err)
+	cfg := Config{
+		Collection:  *collection,
+		Force:       *autoYes,
+		DryRun:      *dryRun,
+		Workers:     *workers,
+		JournalPath: journalPath,
+		BatchID:     newBatchID(),
+		Separators:  parseSeparatorSet(*separators),
+	}
+
+	if cfg.Collection {
+		if !cfg.DryRun && !cfg.Force {
+			fmt.Fprintln(os.Stderr, "Error: -collection processes album directories concurrently, so the interactive confirmation prompt can't be serialized per directory; pass -y (or -dry-run) to run it")
+			os.Exit(1)
+		}
+		bundles := BundleFiles(files)
+		fmt.Printf("Involving %d album directories\n", len(bundles))
+		processCollection(cfg, files)
+	} else {
+		// Group by directory
+		groups := groupFilesByDirectory(files)
+		fmt.Printf("Involving %d directories\n", len(groups))
+
+		// Process each directory
+		for dir, dirFiles := range groups {
+			err := processDirectory(dir, dirFiles, cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to process directory %s: %v\n", dir, err)
+			}
 		}
 	}
 
@@ -425,6 +380,9 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "Usage: audiotool <command> [options]\n\n")
 	fmt.Fprintf(os.Stderr, "Available commands:\n")
 	fmt.Fprintf(os.Stderr, "  removeprefix     Remove common prefix from filenames\n")
+	fmt.Fprintf(os.Stderr, "  organize         Organize files into Artist/Album/Title layout using audio tags\n")
+	fmt.Fprintf(os.Stderr, "  classify         Classify files as Music, Audiobook, Film, Series, or Program\n")
+	fmt.Fprintf(os.Stderr, "  undo             Reverse renames recorded in a rename journal\n")
 	fmt.Fprintf(os.Stderr, "  version          Show version information\n")
 	fmt.Fprintf(os.Stderr, "  help             Show help information\n")
 	fmt.Fprintf(os.Stderr, "\nUse 'audiotool <command> -h' for detailed help on a command\n")
@@ -441,6 +399,12 @@ func main() {
 	switch command {
 	case "removeprefix":
 		removePrefixCommand(os.Args[2:])
+	case "organize":
+		organizeCommand(os.Args[2:])
+	case "classify":
+		classifyCommand(os.Args[2:])
+	case "undo":
+		undoCommand(os.Args[2:])
 	case "version":
 		fmt.Printf("Audio Tool v%s\n", version)
 	case "help", "-h", "--help":
@@ -168,47 +168,47 @@ func TestCollectFiles(t *testing.T) {
 	}
 
 	tests := []struct {
-		name          string
-		excludeDirs   []string
-		extensions    []string
-		expectedMin   int
-		expectedMax   int
-		shouldExclude string
+		name            string
+		excludePatterns []string
+		extensions      []string
+		expectedMin     int
+		expectedMax     int
+		shouldExclude   string
 	}{
 		{
-			name:        "All files, no exclusions",
-			excludeDirs: []string{},
-			extensions:  []string{},
-			expectedMin: 6,
-			expectedMax: 6,
+			name:            "All files, no exclusions",
+			excludePatterns: []string{},
+			extensions:      []string{},
+			expectedMin:     6,
+			expectedMax:     6,
 		},
 		{
-			name:          "Exclude @eaDir",
-			excludeDirs:   []string{"@eaDir"},
-			extensions:    []string{},
-			expectedMin:   5,
-			expectedMax:   5,
-			shouldExclude: "@eaDir",
+			name:            "Exclude @eaDir",
+			excludePatterns: []string{"**/@eaDir/**"},
+			extensions:      []string{},
+			expectedMin:     5,
+			expectedMax:     5,
+			shouldExclude:   "@eaDir",
 		},
 		{
-			name:        "Only audio files (mp3, m4a, flac)",
-			excludeDirs: []string{},
-			extensions:  []string{"mp3", "m4a", "flac"},
-			expectedMin: 4,
-			expectedMax: 4,
+			name:            "Only audio files (mp3, m4a, flac)",
+			excludePatterns: []string{},
+			extensions:      []string{"mp3", "m4a", "flac"},
+			expectedMin:     4,
+			expectedMax:     4,
 		},
 		{
-			name:        "Audio files excluding @eaDir",
-			excludeDirs: []string{"@eaDir"},
-			extensions:  []string{"mp3", "m4a", "flac"},
-			expectedMin: 4,
-			expectedMax: 4,
+			name:            "Audio files excluding @eaDir",
+			excludePatterns: []string{"**/@eaDir/**"},
+			extensions:      []string{"mp3", "m4a", "flac"},
+			expectedMin:     4,
+			expectedMax:     4,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := collectFiles(tmpDir, tt.excludeDirs, tt.extensions)
+			result, err := collectFiles(tmpDir, nil, tt.excludePatterns, tt.extensions)
 			if err != nil {
 				t.Errorf("collectFiles() error = %v", err)
 				return
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Match reports whether name matches the shell-glob pattern, and whether
+// pattern could still match something under name if name were extended
+// with more path components (partial). The partial flag is what lets a
+// directory walker prune whole subtrees that can never match while still
+// descending into directories that might contain a match deeper down.
+//
+// Patterns use path.Match/filepath.Match syntax per component, plus a
+// "**" component that matches zero or more path components (so
+// "**/@eaDir/**" matches an @eaDir directory and everything under it at
+// any depth). Set slashSeparator to true to split on "/" regardless of
+// OS (as when matching user-supplied -include/-exclude patterns).
+func Match(pattern, name string, slashSeparator bool) (match bool, partial bool) {
+	sep := string(filepath.Separator)
+	if slashSeparator {
+		sep = "/"
+	}
+
+	patternParts := strings.Split(pattern, sep)
+	nameParts := strings.Split(name, sep)
+
+	return matchParts(patternParts, nameParts, slashSeparator)
+}
+
+func matchParts(patternParts, nameParts []string, slashSeparator bool) (match bool, partial bool) {
+	for len(patternParts) > 0 && patternParts[0] != "**" {
+		if len(nameParts) == 0 {
+			// The pattern has more literal components than we've walked
+			// into yet; it might still match once the walk goes deeper.
+			return false, true
+		}
+		if !matchComponent(patternParts[0], nameParts[0], slashSeparator) {
+			return false, false
+		}
+		patternParts = patternParts[1:]
+		nameParts = nameParts[1:]
+	}
+
+	if len(patternParts) == 0 {
+		return len(nameParts) == 0, false
+	}
+
+	// patternParts[0] == "**": it may absorb any number (including zero)
+	// of the remaining name components, so try every split point.
+	rest := patternParts[1:]
+	for i := 0; i <= len(nameParts); i++ {
+		if m, _ := matchParts(rest, nameParts[i:], slashSeparator); m {
+			return true, false
+		}
+	}
+	// No split worked with the components seen so far, but "**" can still
+	// absorb components the walker hasn't reached yet.
+	return false, true
+}
+
+func matchComponent(pattern, name string, slashSeparator bool) bool {
+	var ok bool
+	var err error
+	if slashSeparator {
+		ok, err = path.Match(pattern, name)
+	} else {
+		ok, err = filepath.Match(pattern, name)
+	}
+	if err != nil {
+		return false
+	}
+	return ok
+}
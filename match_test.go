@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		pattern         string
+		path            string
+		expectedMatch   bool
+		expectedPartial bool
+	}{
+		{"exact file match", "covers/*.jpg", "covers/front.jpg", true, false},
+		{"exact file no match", "covers/*.jpg", "covers/front.png", false, false},
+		{"doublestar matches nested dir", "**/@eaDir/**", "music/artist/@eaDir/thumb.jpg", true, false},
+		{"doublestar matches top-level dir", "**/@eaDir/**", "@eaDir/thumb.jpg", true, false},
+		{"partial match descends into dir", "**/@eaDir/**", "music", false, true},
+		{"no match and not partial", "*.tmp", "music/song.mp3", false, false},
+		{"partial for shallower ancestor", "a/b/c", "a", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, partial := Match(tt.pattern, tt.path, true)
+			if match != tt.expectedMatch || partial != tt.expectedPartial {
+				t.Errorf("Match(%q, %q) = (%v, %v), want (%v, %v)",
+					tt.pattern, tt.path, match, partial, tt.expectedMatch, tt.expectedPartial)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sanitizePathComponent strips characters that are unsafe in file/directory
+// names on common filesystems (notably Windows) from a single tag value.
+func sanitizePathComponent(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "Unknown"
+	}
+	return s
+}
+
+// organizeTargetPath builds the destination path for a file given its tags,
+// following the `Artist/Album/NN - Title.ext` layout.
+func organizeTargetPath(destRoot string, info Info, ext string) string {
+	artist := sanitizePathComponent(info.Artist)
+	album := sanitizePathComponent(info.Album)
+	title := sanitizePathComponent(info.Title)
+
+	var name string
+	if info.Track > 0 {
+		name = fmt.Sprintf("%02d - %s%s", info.Track, title, ext)
+	} else {
+		name = title + ext
+	}
+
+	return filepath.Join(destRoot, artist, album, name)
+}
+
+// organizeCommand is the subcommand that renames/moves files into an
+// Artist/Album/NN - Title layout based on embedded tags rather than the
+// byte-prefix heuristic used by removeprefix.
+func organizeCommand(args []string) {
+	fs := flag.NewFlagSet("organize", flag.ExitOnError)
+	dir := fs.String("dir", ".", "Directory path to scan for audio files")
+	dest := fs.String("dest", "", "Destination root directory (defaults to -dir)")
+	backend := fs.String("backend", "ffprobe", "Tag reading backend (ffprobe is currently the only option)")
+	dryRun := fs.Bool("dry-run", false, "Preview mode, don't actually move files")
+	autoYes := fs.Bool("y", false, "Auto-confirm all operations without asking")
+	exts := fs.String("exts", "mp3,m4a,flac,wav,ogg", "Comma-separated list of file extensions to process")
+	journalPath := fs.String("journal", "", "Path to the rename journal, consumed by 'audiotool undo' (default: .audiotool-journal.jsonl in -dest)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: audiotool organize [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Read embedded audio tags and move/rename files into an Artist/Album/NN - Title layout\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  audiotool organize -dir /path/to/music -dry-run\n")
+		fmt.Fprintf(os.Stderr, "  audiotool organize -dir /path/to/music -dest /path/to/library -y\n")
+		fmt.Fprintf(os.Stderr, "  audiotool undo -dir /path/to/library -last\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	reader, err := newTagReader(*backend)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	absDir, err := filepath.Abs(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: unable to get absolute path: %v\n", err)
+		os.Exit(1)
+	}
+
+	destRoot := *dest
+	if destRoot == "" {
+		destRoot = absDir
+	} else if destRoot, err = filepath.Abs(destRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: unable to get absolute path: %v\n", err)
+		os.Exit(1)
+	}
+
+	var extList []string
+	if *exts != "" {
+		extList = strings.Split(*exts, ",")
+		for i, ext := range extList {
+			extList[i] = strings.TrimSpace(ext)
+		}
+	}
+
+	files, err := collectFiles(absDir, nil, nil, extList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to collect files: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(files) == 0 {
+		fmt.Println("No files found")
+		return
+	}
+
+	fmt.Printf("Found %d files in total\n", len(files))
+
+	type move struct {
+		oldPath string
+		newPath string
+	}
+	var moves []move
+	plannedDests := make(map[string]string) // newPath -> oldPath, to catch two sources mapping to the same destination
+
+	for _, file := range files {
+		info, err := reader.Read(file)
+		if err != nil {
+			fmt.Printf("Warning: failed to read tags for %s: %v\n", file, err)
+			continue
+		}
+		if info.Artist == "" && info.Album == "" && info.Title == "" {
+			fmt.Printf("Warning: no usable tags found, skipping: %s\n", file)
+			continue
+		}
+		if info.Title == "" {
+			info.Title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+
+		newPath := organizeTargetPath(destRoot, info, filepath.Ext(file))
+		if newPath == file {
+			continue
+		}
+		if conflict, ok := plannedDests[newPath]; ok {
+			fmt.Printf("Warning: %s and %s both map to %s, skipping %s\n", conflict, file, newPath, file)
+			continue
+		}
+		plannedDests[newPath] = file
+		moves = append(moves, move{oldPath: file, newPath: newPath})
+	}
+
+	if len(moves) == 0 {
+		fmt.Println("Nothing to organize")
+		return
+	}
+
+	fmt.Println("Organize preview (showing first 5):")
+	displayCount := 5
+	if len(moves) < displayCount {
+		displayCount = len(moves)
+	}
+	for i := 0; i < displayCount; i++ {
+		fmt.Printf("  %s\n  -> %s\n\n", moves[i].oldPath, moves[i].newPath)
+	}
+	if len(moves) > displayCount {
+		fmt.Printf("  ... and %d more files\n\n", len(moves)-displayCount)
+	}
+
+	if *dryRun {
+		fmt.Println("[Preview Mode] No actual moving performed")
+		return
+	}
+
+	proceed := *autoYes
+	if !*autoYes {
+		fmt.Printf("Proceed to organize these %d files? (y/n): ", len(moves))
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		response = strings.ToLower(strings.TrimSpace(response))
+		proceed = response == "y" || response == "yes"
+	}
+
+	if !proceed {
+		fmt.Println("Cancelled")
+		return
+	}
+
+	journal := *journalPath
+	if journal == "" {
+		journal = defaultJournalPath(destRoot)
+	}
+	batchID := newBatchID()
+
+	successCount := 0
+	for _, m := range moves {
+		if err := os.MkdirAll(filepath.Dir(m.newPath), 0755); err != nil {
+			fmt.Printf("Error: failed to create directory for %s: %v\n", m.newPath, err)
+			continue
+		}
+		if _, err := os.Stat(m.newPath); err == nil {
+			fmt.Printf("Skipping %s: a file already exists at %s\n", m.oldPath, m.newPath)
+			continue
+		}
+		if err := os.Rename(m.oldPath, m.newPath); err != nil {
+			fmt.Printf("Error: failed to move %s: %v\n", m.oldPath, err)
+			continue
+		}
+		if err := recordRename(journal, m.oldPath, m.newPath, batchID); err != nil {
+			fmt.Printf("Warning: move succeeded but failed to journal it: %v\n", err)
+		}
+		successCount++
+	}
+
+	fmt.Printf("Successfully organized %d/%d files\n", successCount, len(moves))
+}
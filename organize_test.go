@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSanitizePathComponent(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain string is unchanged", "Some Artist", "Some Artist"},
+		{"unsafe characters are replaced", `A/B\C:D*E?F"G<H>I|J`, "A_B_C_D_E_F_G_H_I_J"},
+		{"leading and trailing space is trimmed", "  Artist  ", "Artist"},
+		{"empty string becomes Unknown", "", "Unknown"},
+		{"whitespace-only string becomes Unknown", "   ", "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sanitizePathComponent(tt.input); result != tt.expected {
+				t.Errorf("sanitizePathComponent(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOrganizeTargetPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     Info
+		ext      string
+		expected string
+	}{
+		{
+			name:     "with track number",
+			info:     Info{Artist: "Artist", Album: "Album", Title: "Title", Track: 3},
+			ext:      ".mp3",
+			expected: filepath.Join("/dest", "Artist", "Album", "03 - Title.mp3"),
+		},
+		{
+			name:     "without track number",
+			info:     Info{Artist: "Artist", Album: "Album", Title: "Title"},
+			ext:      ".flac",
+			expected: filepath.Join("/dest", "Artist", "Album", "Title.flac"),
+		},
+		{
+			name:     "unsafe characters sanitized",
+			info:     Info{Artist: "A/B", Album: "C:D", Title: "E?F"},
+			ext:      ".m4a",
+			expected: filepath.Join("/dest", "A_B", "C_D", "E_F.m4a"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := organizeTargetPath("/dest", tt.info, tt.ext); result != tt.expected {
+				t.Errorf("organizeTargetPath() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SeparatorSet is the set of runes treated as boundaries when trimming a
+// detected common prefix back to a "clean" cut point (e.g. the space after
+// "Common Prefix " or the 】 closing a Chinese-style 【tag】).
+type SeparatorSet struct {
+	runes map[rune]bool
+}
+
+// defaultSeparators covers the separators the original byte-oriented
+// implementation special-cased (-, _, space, ), ]) plus the CJK bracket
+// pairs, full-width punctuation, and dash variants commonly seen in
+// "【歌单】", "（专辑）", and similar filename prefixes.
+var defaultSeparators = newSeparatorSet([]rune{
+	'-', '_', ' ', ')', ']',
+	'【', '】', '《', '》', '「', '」',
+	'－', // fullwidth hyphen-minus －
+	'＿', // fullwidth low line ＿
+	'　', // ideographic space
+	'–', // en dash –
+	'—', // em dash —
+})
+
+func newSeparatorSet(runes []rune) SeparatorSet {
+	s := SeparatorSet{runes: make(map[rune]bool, len(runes))}
+	for _, r := range runes {
+		s.runes[r] = true
+	}
+	return s
+}
+
+// Contains reports whether r is considered a separator.
+func (s SeparatorSet) Contains(r rune) bool {
+	return s.runes[r]
+}
+
+// parseSeparatorSet parses a comma-separated list of single-rune separators
+// (as passed via the -separators flag) and merges them into the defaults.
+func parseSeparatorSet(spec string) SeparatorSet {
+	set := newSeparatorSet(nil)
+	for r := range defaultSeparators.runes {
+		set.runes[r] = true
+	}
+	if spec == "" {
+		return set
+	}
+	for _, tok := range strings.Split(spec, ",") {
+		runes := []rune(tok)
+		if len(runes) != 1 {
+			continue
+		}
+		set.runes[runes[0]] = true
+	}
+	return set
+}
+
+// isCombiningMark reports whether r is a combining mark that should stay
+// attached to the preceding base rune (the stdlib-only approximation of a
+// full grapheme-cluster boundary check; see note on normalizeForComparison).
+func isCombiningMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me)
+}
+
+// zwj is ZERO WIDTH JOINER, used to glue emoji into a single displayed
+// glyph (e.g. a family emoji is several person/role emoji joined by zwj).
+const zwj = '\u200D'
+
+// isRegionalIndicator reports whether r is one of the 26 regional indicator
+// symbols (U+1F1E6-U+1F1FF). Flag emoji are always exactly two of these in
+// a row (e.g. "US" -> 🇺🇸); splitting between them leaves two dangling
+// letter-in-a-box glyphs instead of one flag.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// trimIncompleteCluster pulls length back, if necessary, so it never splits
+// a flag emoji (an odd-length run of trailing regional indicators) or a
+// ZWJ-joined sequence (a trailing ZWJ with nothing joined after it). This
+// covers the two grapheme-cluster shapes stdlib's unicode package can
+// recognize without a dedicated segmenter.
+func trimIncompleteCluster(runes []rune, length int) int {
+	for length > 0 && runes[length-1] == zwj {
+		length--
+	}
+	riCount := 0
+	for i := length - 1; i >= 0 && isRegionalIndicator(runes[i]); i-- {
+		riCount++
+	}
+	if riCount%2 == 1 {
+		length--
+	}
+	return length
+}
+
+// normalizeForComparison is the hook where full NFC normalization would
+// live so that NFD filenames from macOS and NFC filenames from Linux group
+// together. That requires golang.org/x/text/unicode/norm, which isn't
+// available to this stdlib-only module, so this ticket is scoped down to
+// the grapheme-cluster splitting bugs (flag emoji, ZWJ sequences) that
+// trimIncompleteCluster and isCombiningMark guard against; NFD/NFC
+// filename grouping is explicitly out of scope until that dependency can
+// be added, and this remains the identity function.
+func normalizeForComparison(s string) string {
+	return s
+}
+
+// findCommonPrefix finds the longest common prefix among all strings,
+// operating on runes so a cut never lands in the middle of a multi-byte
+// codepoint, and trimmed back to the last SeparatorSet boundary (keeping a
+// trailing combining mark attached to its base rune) so the cut never
+// splits a basic grapheme cluster either.
+func findCommonPrefix(strs []string) string {
+	return findCommonPrefixWithSeparators(strs, defaultSeparators)
+}
+
+// findCommonPrefixWithSeparators is findCommonPrefix with a caller-supplied
+// SeparatorSet, e.g. one extended via -separators.
+func findCommonPrefixWithSeparators(strs []string, seps SeparatorSet) string {
+	if len(strs) < 2 {
+		return ""
+	}
+
+	runeStrs := make([][]rune, len(strs))
+	for i, s := range strs {
+		runeStrs[i] = []rune(normalizeForComparison(s))
+	}
+
+	minLen := len(runeStrs[0])
+	for _, rs := range runeStrs {
+		if len(rs) < minLen {
+			minLen = len(rs)
+		}
+	}
+
+	prefixLen := 0
+	for i := 0; i < minLen; i++ {
+		r := runeStrs[0][i]
+		allMatch := true
+		for _, rs := range runeStrs {
+			if rs[i] != r {
+				allMatch = false
+				break
+			}
+		}
+		if !allMatch {
+			break
+		}
+		prefixLen = i + 1
+	}
+
+	prefixLen = trimIncompleteCluster(runeStrs[0], prefixLen)
+	if prefixLen == 0 {
+		return ""
+	}
+
+	return cutAtSeparator(runeStrs[0][:prefixLen], seps)
+}
+
+// findMajorityPrefix finds a common prefix shared by at least 70% of
+// strs, to tolerate a handful of outlier files that don't share the
+// common prefix.
+func findMajorityPrefix(strs []string) string {
+	return findMajorityPrefixWithSeparators(strs, defaultSeparators)
+}
+
+// findMajorityPrefixWithSeparators is findMajorityPrefix with a
+// caller-supplied SeparatorSet.
+func findMajorityPrefixWithSeparators(strs []string, seps SeparatorSet) string {
+	if len(strs) < 2 {
+		return ""
+	}
+
+	runeStrs := make([][]rune, len(strs))
+	for i, s := range strs {
+		runeStrs[i] = []rune(normalizeForComparison(s))
+	}
+
+	threshold := int(float64(len(strs)) * 0.7)
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	bestPrefix := ""
+	bestMatchCount := 0
+
+	for _, source := range runeStrs {
+		for length := len(source); length >= 3; length-- {
+			length = trimIncompleteCluster(source, length)
+			if length < 3 {
+				break
+			}
+			candidate := source[:length]
+
+			matchCount := 0
+			for _, rs := range runeStrs {
+				if hasRunePrefix(rs, candidate) {
+					matchCount++
+				}
+			}
+
+			if matchCount < threshold || matchCount <= bestMatchCount {
+				continue
+			}
+
+			trimmed := cutAtSeparator(candidate, seps)
+			if trimmed == "" {
+				continue
+			}
+			if len(strings.TrimSpace(trimmed)) < 3 {
+				continue
+			}
+
+			bestPrefix = trimmed
+			bestMatchCount = matchCount
+		}
+	}
+
+	return bestPrefix
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// cutAtSeparator trims prefix back to just after the last separator rune it
+// contains (skipping back over any combining marks attached to that
+// separator or to the base rune before it), mirroring the original
+// byte-based "smart trimming" but without risking a split mid-codepoint or
+// mid-cluster. If no separator is found, the full prefix is returned.
+func cutAtSeparator(prefix []rune, seps SeparatorSet) string {
+	lastSep := -1
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if isCombiningMark(prefix[i]) {
+			continue
+		}
+		if seps.Contains(prefix[i]) {
+			lastSep = i + 1
+			break
+		}
+	}
+
+	if lastSep > 0 && lastSep < len(prefix) {
+		return string(prefix[:lastSep])
+	}
+
+	return string(prefix)
+}
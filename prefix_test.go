@@ -0,0 +1,104 @@
+package main
+
+import "testing"
+
+func TestFindCommonPrefixUnicodeSeparators(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name: "Fullwidth dash separator",
+			input: []string{
+				"歌手Ａ－曲目1.mp3",
+				"歌手Ａ－曲目2.mp3",
+			},
+			expected: "歌手Ａ－",
+		},
+		{
+			name: "Em dash separator",
+			input: []string{
+				"Collection—Track1.flac",
+				"Collection—Track2.flac",
+			},
+			expected: "Collection—",
+		},
+		{
+			name: "Japanese corner brackets",
+			input: []string{
+				"「Album」Song1.m4a",
+				"「Album」Song2.m4a",
+			},
+			expected: "「Album」",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := findCommonPrefix(tt.input); result != tt.expected {
+				t.Errorf("findCommonPrefix() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindCommonPrefixDoesNotSplitCombiningMark(t *testing.T) {
+	// "é" here is "e" + U+0301 COMBINING ACUTE ACCENT, a two-rune cluster.
+	// The shared prefix naturally ends right after it; cutAtSeparator must
+	// not be fooled into treating the combining mark itself as a cut point.
+	input := []string{"Café-Track1.mp3", "Café-Track2.mp3"}
+	expected := "Café-"
+
+	if result := findCommonPrefix(input); result != expected {
+		t.Errorf("findCommonPrefix() = %q, want %q", result, expected)
+	}
+}
+
+func TestParseSeparatorSet(t *testing.T) {
+	set := parseSeparatorSet("~,·")
+
+	if !set.Contains('~') || !set.Contains('·') {
+		t.Error("expected custom separators to be present")
+	}
+	if !set.Contains('-') || !set.Contains('】') {
+		t.Error("expected built-in defaults to still be present")
+	}
+	if set.Contains('x') {
+		t.Error("did not expect 'x' to be treated as a separator")
+	}
+}
+
+func TestFindCommonPrefixDoesNotSplitFlagEmoji(t *testing.T) {
+	// "🇺🇸" is two regional indicator symbols (U+1F1FA U+1F1F8); a cut
+	// between them would leave one file with a dangling "🇺" instead of the
+	// shared "🇺🇸-" prefix.
+	input := []string{"🇺🇸-Track1.mp3", "🇺🇸-Track2.mp3"}
+	expected := "🇺🇸-"
+
+	if result := findCommonPrefix(input); result != expected {
+		t.Errorf("findCommonPrefix() = %q, want %q", result, expected)
+	}
+}
+
+func TestFindCommonPrefixDoesNotSplitZWJSequence(t *testing.T) {
+	// "👨‍👩‍👧" is three person emoji joined by U+200D ZERO WIDTH JOINER into
+	// one family glyph; a cut mid-sequence would leave a dangling joiner.
+	input := []string{"👨‍👩‍👧 Family-Track1.mp3", "👨‍👩‍👧 Family-Track2.mp3"}
+	expected := "👨‍👩‍👧 Family-"
+
+	if result := findCommonPrefix(input); result != expected {
+		t.Errorf("findCommonPrefix() = %q, want %q", result, expected)
+	}
+}
+
+func TestFindCommonPrefixWithSeparatorsIgnoresUnconfigured(t *testing.T) {
+	seps := newSeparatorSet([]rune{'~'})
+	input := []string{"Tag-Song1.mp3", "Tag-Song2.mp3"}
+
+	// '-' isn't in this custom set, so the cut should fall back to the
+	// full matched prefix instead of trimming at the dash.
+	if result := findCommonPrefixWithSeparators(input, seps); result != "Tag-Song" {
+		t.Errorf("findCommonPrefixWithSeparators() = %q, want %q", result, "Tag-Song")
+	}
+}
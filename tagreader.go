@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Info holds the common metadata fields extracted from an audio file's tags.
+type Info struct {
+	Artist string
+	Album  string
+	Title  string
+	Track  int
+	Year   int
+}
+
+// TagReader reads embedded metadata from an audio file.
+// Implementations are free to shell out to external tools; callers should
+// treat a non-nil error as "no usable tags" rather than a fatal condition.
+type TagReader interface {
+	Read(path string) (Info, error)
+}
+
+// ffprobeReader reads tags via the "ffprobe" binary, which must be present
+// on PATH. It is the default backend since ffprobe ships with most ffmpeg
+// installs and handles the broadest range of container formats.
+type ffprobeReader struct{}
+
+type ffprobeFormat struct {
+	Format struct {
+		Tags map[string]string `json:"tags"`
+	} `json:"format"`
+}
+
+func (ffprobeReader) Read(path string) (Info, error) {
+	cmd := exec.Command("ffprobe", "-v", "quiet", "-print_format", "json", "-show_format", path)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return Info{}, fmt.Errorf("ffprobe: %w", err)
+	}
+
+	var parsed ffprobeFormat
+	if err := json.Unmarshal(out.Bytes(), &parsed); err != nil {
+		return Info{}, fmt.Errorf("ffprobe: parse output: %w", err)
+	}
+
+	return infoFromTags(parsed.Format.Tags), nil
+}
+
+// infoFromTags normalizes the handful of tag-key spellings that ffprobe
+// commonly uses into an Info struct.
+func infoFromTags(tags map[string]string) Info {
+	get := func(keys ...string) string {
+		for _, k := range keys {
+			if v, ok := tags[k]; ok && v != "" {
+				return v
+			}
+			if v, ok := tags[strings.ToUpper(k)]; ok && v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	info := Info{
+		Artist: get("artist"),
+		Album:  get("album"),
+		Title:  get("title"),
+	}
+
+	if track := get("track"); track != "" {
+		// Some containers store "3/12"; keep just the track number.
+		track, _, _ = strings.Cut(track, "/")
+		if n, err := strconv.Atoi(strings.TrimSpace(track)); err == nil {
+			info.Track = n
+		}
+	}
+
+	if year := get("date", "year"); year != "" {
+		if len(year) >= 4 {
+			year = year[:4]
+		}
+		if n, err := strconv.Atoi(year); err == nil {
+			info.Year = n
+		}
+	}
+
+	return info
+}
+
+// newTagReader selects a TagReader implementation by name. ffprobe is
+// currently the only backend; a pure-Go or cgo taglib backend can be added
+// here once one exists, rather than shelling out to an invented binary.
+func newTagReader(backend string) (TagReader, error) {
+	switch backend {
+	case "", "ffprobe":
+		return ffprobeReader{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tag backend %q (want ffprobe)", backend)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestInfoFromTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		expected Info
+	}{
+		{
+			name: "lowercase keys",
+			tags: map[string]string{
+				"artist": "Some Artist",
+				"album":  "Some Album",
+				"title":  "Some Title",
+				"track":  "3",
+				"date":   "2001-05-01",
+			},
+			expected: Info{Artist: "Some Artist", Album: "Some Album", Title: "Some Title", Track: 3, Year: 2001},
+		},
+		{
+			name: "uppercase keys fall back",
+			tags: map[string]string{
+				"ARTIST": "Some Artist",
+				"ALBUM":  "Some Album",
+				"TITLE":  "Some Title",
+			},
+			expected: Info{Artist: "Some Artist", Album: "Some Album", Title: "Some Title"},
+		},
+		{
+			name:     "track number with total",
+			tags:     map[string]string{"track": "3/12"},
+			expected: Info{Track: 3},
+		},
+		{
+			name:     "year falls back to year key",
+			tags:     map[string]string{"year": "1999"},
+			expected: Info{Year: 1999},
+		},
+		{
+			name:     "empty tags",
+			tags:     map[string]string{},
+			expected: Info{},
+		},
+		{
+			name:     "non-numeric track is ignored",
+			tags:     map[string]string{"track": "unknown"},
+			expected: Info{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := infoFromTags(tt.tags); result != tt.expected {
+				t.Errorf("infoFromTags(%v) = %+v, want %+v", tt.tags, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewTagReader(t *testing.T) {
+	if _, err := newTagReader(""); err != nil {
+		t.Errorf("newTagReader(\"\") returned error: %v", err)
+	}
+	if _, err := newTagReader("ffprobe"); err != nil {
+		t.Errorf("newTagReader(\"ffprobe\") returned error: %v", err)
+	}
+	if _, err := newTagReader("taglib"); err == nil {
+		t.Error("newTagReader(\"taglib\") expected an error, got nil (no taglib backend exists)")
+	}
+	if _, err := newTagReader("bogus"); err == nil {
+		t.Error("newTagReader(\"bogus\") expected an error, got nil")
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// selectBatch returns the batch ID to undo: requested if non-empty,
+// otherwise the batch of the most recently written record.
+func selectBatch(records []journalRecord, requested string) string {
+	if requested != "" {
+		return requested
+	}
+	return records[len(records)-1].BatchID
+}
+
+// planUndo picks out the rename records for targetBatch and returns them in
+// reverse-chronological order, so that a chain of renames on the same file
+// unwinds correctly when replayed in that order.
+func planUndo(records []journalRecord, targetBatch string) []journalRecord {
+	var toUndo []journalRecord
+	for _, rec := range records {
+		if rec.Operation == "rename" && rec.BatchID == targetBatch {
+			toUndo = append(toUndo, rec)
+		}
+	}
+	for i, j := 0, len(toUndo)-1; i < j; i, j = i+1, j-1 {
+		toUndo[i], toUndo[j] = toUndo[j], toUndo[i]
+	}
+	return toUndo
+}
+
+// pathState is the bit of filesystem state undoCollisionReason needs about
+// rec.NewPath, decoupled from os.Stat so the collision logic can be tested
+// without touching a filesystem.
+type pathState struct {
+	exists  bool
+	size    int64
+	modTime string
+}
+
+// statPathState stats path and reports its pathState. A not-exist error is
+// reported as a zero-value (non-existent) state rather than an error; any
+// other stat failure (e.g. permission denied) is returned as err.
+func statPathState(path string) (pathState, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return pathState{}, nil
+	}
+	if err != nil {
+		return pathState{}, err
+	}
+	return pathState{
+		exists:  true,
+		size:    info.Size(),
+		modTime: info.ModTime().UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// undoCollisionReason reports why rec should not be undone given the
+// current state of its new path (newState) and whether its old path already
+// exists (oldPathExists). Returns "" if it's safe to undo.
+func undoCollisionReason(rec journalRecord, newState pathState, oldPathExists bool) string {
+	if !newState.exists {
+		return fmt.Sprintf("no longer exists at %s", rec.NewPath)
+	}
+	if newState.size != rec.Size || newState.modTime != rec.ModTime {
+		return fmt.Sprintf("%s has been modified since the rename, refusing to undo", rec.NewPath)
+	}
+	if oldPathExists {
+		return fmt.Sprintf("a file already exists at %s, refusing to overwrite", rec.OldPath)
+	}
+	return ""
+}
+
+// undoCommand is the subcommand that replays a rename journal in reverse,
+// restoring files renamed by a previous removeprefix/organize run.
+func undoCommand(args []string) {
+	fs := flag.NewFlagSet("undo", flag.ExitOnError)
+	dir := fs.String("dir", ".", "Directory the journal lives in (used to resolve the default -journal path)")
+	journalPath := fs.String("journal", "", "Path to the rename journal (default: .audiotool-journal.jsonl in -dir)")
+	batch := fs.String("batch", "", "Only undo renames from this batch ID")
+	last := fs.Bool("last", false, "Undo only the most recent batch in the journal")
+	dryRun := fs.Bool("dry-run", false, "Print the planned reversions without touching any files")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: audiotool undo [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Reverse renames recorded in a rename journal\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  audiotool undo -dir /path/to/music -last\n")
+		fmt.Fprintf(os.Stderr, "  audiotool undo -dir /path/to/music -batch 20260101T000000.000000000Z\n")
+		fmt.Fprintf(os.Stderr, "  audiotool undo -dir /path/to/music -last -dry-run\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *batch != "" && *last {
+		fmt.Fprintf(os.Stderr, "Error: -batch and -last are mutually exclusive\n")
+		os.Exit(1)
+	}
+
+	absDir, err := filepath.Abs(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: unable to get absolute path: %v\n", err)
+		os.Exit(1)
+	}
+
+	path := *journalPath
+	if path == "" {
+		path = defaultJournalPath(absDir)
+	}
+
+	records, err := readJournal(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read journal %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	if len(records) == 0 {
+		fmt.Println("Journal is empty, nothing to undo")
+		return
+	}
+
+	// Default to the most recently written batch, whether or not -last was
+	// passed explicitly.
+	targetBatch := selectBatch(records, *batch)
+
+	toUndo := planUndo(records, targetBatch)
+	if len(toUndo) == 0 {
+		fmt.Printf("No renames found for batch %s\n", targetBatch)
+		return
+	}
+
+	fmt.Printf("Undoing %d rename(s) from batch %s\n", len(toUndo), targetBatch)
+
+	for _, rec := range toUndo {
+		newState, err := statPathState(rec.NewPath)
+		if err != nil {
+			fmt.Printf("Skipping %s: %v\n", rec.OldPath, err)
+			continue
+		}
+		_, oldErr := os.Stat(rec.OldPath)
+		oldPathExists := oldErr == nil
+
+		if reason := undoCollisionReason(rec, newState, oldPathExists); reason != "" {
+			fmt.Printf("Skipping %s: %s\n", rec.OldPath, reason)
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("  %s\n  -> %s\n\n", rec.NewPath, rec.OldPath)
+			continue
+		}
+
+		if err := os.Rename(rec.NewPath, rec.OldPath); err != nil {
+			fmt.Printf("Error: failed to undo rename of %s: %v\n", rec.NewPath, err)
+			continue
+		}
+		if err := appendJournal(path, journalRecord{
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+			OldPath:   rec.NewPath,
+			NewPath:   rec.OldPath,
+			Operation: "undo",
+			BatchID:   targetBatch,
+		}); err != nil {
+			fmt.Printf("Warning: undo succeeded but failed to journal it: %v\n", err)
+		}
+		fmt.Printf("Restored %s\n", rec.OldPath)
+	}
+
+	if *dryRun {
+		fmt.Println("\n[Preview Mode] No files were changed")
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSelectBatch(t *testing.T) {
+	records := []journalRecord{
+		{BatchID: "batch-1"},
+		{BatchID: "batch-2"},
+	}
+
+	if got := selectBatch(records, "batch-1"); got != "batch-1" {
+		t.Errorf("selectBatch() with explicit batch = %q, want %q", got, "batch-1")
+	}
+	if got := selectBatch(records, ""); got != "batch-2" {
+		t.Errorf("selectBatch() with no batch = %q, want most recent %q", got, "batch-2")
+	}
+}
+
+func TestPlanUndo(t *testing.T) {
+	records := []journalRecord{
+		{OldPath: "a.mp3", NewPath: "b.mp3", Operation: "rename", BatchID: "batch-1"},
+		{OldPath: "b.mp3", NewPath: "c.mp3", Operation: "rename", BatchID: "batch-1"},
+		{OldPath: "c.mp3", NewPath: "b.mp3", Operation: "undo", BatchID: "batch-1"},
+		{OldPath: "x.mp3", NewPath: "y.mp3", Operation: "rename", BatchID: "batch-2"},
+	}
+
+	plan := planUndo(records, "batch-1")
+	if len(plan) != 2 {
+		t.Fatalf("planUndo() returned %d records, want 2", len(plan))
+	}
+	// Reverse chronological: the b.mp3->c.mp3 rename undoes before a.mp3->b.mp3.
+	if plan[0].OldPath != "b.mp3" || plan[1].OldPath != "a.mp3" {
+		t.Errorf("planUndo() order = %+v, want reverse-chronological", plan)
+	}
+}
+
+func TestPlanUndoNoMatch(t *testing.T) {
+	records := []journalRecord{
+		{OldPath: "a.mp3", NewPath: "b.mp3", Operation: "rename", BatchID: "batch-1"},
+	}
+	if plan := planUndo(records, "batch-2"); len(plan) != 0 {
+		t.Errorf("planUndo() for unknown batch = %+v, want empty", plan)
+	}
+}
+
+func TestUndoCollisionReasonStaleModTime(t *testing.T) {
+	rec := journalRecord{OldPath: "old.mp3", NewPath: "new.mp3", Size: 10, ModTime: "2026-01-01T00:00:00Z"}
+	newState := pathState{exists: true, size: 10, modTime: "2026-01-02T00:00:00Z"}
+
+	if reason := undoCollisionReason(rec, newState, false); reason == "" {
+		t.Error("undoCollisionReason() = \"\", want a refusal for a stale mtime")
+	}
+}
+
+func TestUndoCollisionReasonMissingNewPath(t *testing.T) {
+	rec := journalRecord{OldPath: "old.mp3", NewPath: "new.mp3", Size: 10, ModTime: "2026-01-01T00:00:00Z"}
+	newState := pathState{exists: false}
+
+	if reason := undoCollisionReason(rec, newState, false); reason == "" {
+		t.Error("undoCollisionReason() = \"\", want a refusal when new path no longer exists")
+	}
+}
+
+func TestUndoCollisionReasonOldPathAlreadyExists(t *testing.T) {
+	rec := journalRecord{OldPath: "old.mp3", NewPath: "new.mp3", Size: 10, ModTime: "2026-01-01T00:00:00Z"}
+	newState := pathState{exists: true, size: 10, modTime: "2026-01-01T00:00:00Z"}
+
+	if reason := undoCollisionReason(rec, newState, true); reason == "" {
+		t.Error("undoCollisionReason() = \"\", want a refusal when the old path already exists")
+	}
+}
+
+func TestUndoCollisionReasonSafe(t *testing.T) {
+	rec := journalRecord{OldPath: "old.mp3", NewPath: "new.mp3", Size: 10, ModTime: "2026-01-01T00:00:00Z"}
+	newState := pathState{exists: true, size: 10, modTime: "2026-01-01T00:00:00Z"}
+
+	if reason := undoCollisionReason(rec, newState, false); reason != "" {
+		t.Errorf("undoCollisionReason() = %q, want \"\" for a safe undo", reason)
+	}
+}
+
+func TestStatPathState(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.mp3")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := statPathState(path)
+	if err != nil {
+		t.Fatalf("statPathState() error = %v", err)
+	}
+	if !state.exists || state.size != int64(len("hello")) {
+		t.Errorf("statPathState() = %+v, want exists with size %d", state, len("hello"))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.modTime != info.ModTime().UTC().Format(time.RFC3339Nano) {
+		t.Errorf("statPathState() modTime = %q, want %q", state.modTime, info.ModTime().UTC().Format(time.RFC3339Nano))
+	}
+
+	missing, err := statPathState(filepath.Join(tmpDir, "missing.mp3"))
+	if err != nil {
+		t.Fatalf("statPathState() for missing file returned error: %v", err)
+	}
+	if missing.exists {
+		t.Error("statPathState() for missing file reported exists = true")
+	}
+}